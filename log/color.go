@@ -0,0 +1,58 @@
+package log
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode controls whether a Logger emits ANSI color escapes.
+type ColorMode int
+
+const (
+	// ColorAuto colors a line only when the sink it is written to is
+	// detected to be an interactive terminal. This is the zero value so
+	// that a zero-value LoggerInfo gets sensible auto-detection.
+	ColorAuto ColorMode = iota
+	// ColorOn always colors output, regardless of what the sink is.
+	ColorOn
+	// ColorOff never colors output.
+	ColorOff
+)
+
+// colorEnabled decides whether output to f should be colored, taking both
+// the new Color mode and the legacy UseColors flag into account.
+func (li *LoggerInfo) colorEnabled(f *os.File) bool {
+	if li.UseColors && li.Color == ColorAuto {
+		return true
+	}
+
+	switch li.Color {
+	case ColorOn:
+		return true
+	case ColorOff:
+		return false
+	default:
+		return isTerminal(f)
+	}
+}
+
+// isTerminal reports whether f is attached to an interactive terminal,
+// including the Cygwin/MSYS terminals found on Windows.
+func isTerminal(f *os.File) bool {
+	fd := f.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// splitHeader splits a rendered line into its leading whitespace-delimited
+// token (the level header, e.g. "WARN") and the remainder, so
+// ColorHeaderOnly can color just the header and leave the message body in
+// the default color.
+func splitHeader(msg string) (header, rest string) {
+	idx := strings.IndexByte(msg, ' ')
+	if idx < 0 {
+		return msg, ""
+	}
+	return msg[:idx], msg[idx:]
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"os"
+	"testing"
+)
+
+func TestColorEnabledModes(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	li := &LoggerInfo{Color: ColorOn}
+	if !li.colorEnabled(devNull) {
+		t.Fatalf("ColorOn must enable color regardless of the sink")
+	}
+
+	li = &LoggerInfo{Color: ColorOff}
+	if li.colorEnabled(devNull) {
+		t.Fatalf("ColorOff must disable color regardless of the sink")
+	}
+
+	li = &LoggerInfo{Color: ColorAuto}
+	if li.colorEnabled(devNull) {
+		t.Fatalf("ColorAuto must not color a non-terminal sink")
+	}
+
+	li = &LoggerInfo{UseColors: true}
+	if !li.colorEnabled(devNull) {
+		t.Fatalf("the legacy UseColors flag must still force color on")
+	}
+}
+
+func TestSplitHeader(t *testing.T) {
+	header, rest := splitHeader("WARN something happened")
+	if header != "WARN" || rest != " something happened" {
+		t.Fatalf("got %q / %q", header, rest)
+	}
+
+	header, rest = splitHeader("nofields")
+	if header != "nofields" || rest != "" {
+		t.Fatalf("got %q / %q", header, rest)
+	}
+}
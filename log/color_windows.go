@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package log
+
+import (
+	"os"
+
+	"github.com/mattn/go-colorable"
+)
+
+// On Windows, cmd.exe and older PowerShell hosts don't interpret ANSI
+// escape sequences natively, so go-colortext's writes would otherwise show
+// up as garbage. Wrap stdout/stderr in colorable writers that translate
+// the escapes into the equivalent Win32 console API calls.
+func init() {
+	stdOut = colorable.NewColorable(os.Stdout)
+	stdErr = colorable.NewColorable(os.Stderr)
+}
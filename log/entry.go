@@ -0,0 +1,163 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Entry is a single structured log record that is handed to every
+// registered Logger. It carries everything a Logger needs to render the
+// line: the level and message exactly as before, plus the time, the
+// caller and any contextual fields attached through WithFields.
+type Entry struct {
+	// Level is the same level value that used to be passed directly to
+	// Log(level, msg).
+	Level int
+	// Time is when the entry was created.
+	Time time.Time
+	// Caller is the file:line of the call site, when available.
+	Caller string
+	// Message is the formatted log message.
+	Message string
+	// Fields holds arbitrary contextual key/value pairs attached via
+	// WithFields. It is nil when no fields were set.
+	Fields map[string]interface{}
+}
+
+// newEntry builds an Entry for the given level and message, merging in the
+// given fields. It is the common formatting layer that every Log call,
+// whether it originates from a plain level/message pair or from a
+// FieldLogger, funnels through.
+func newEntry(level int, msg string, fields map[string]interface{}) Entry {
+	return Entry{
+		Level:   level,
+		Time:    time.Now(),
+		Caller:  caller(3),
+		Message: msg,
+		Fields:  fields,
+	}
+}
+
+// caller returns the "file:line" of the calling goroutine, skip frames up
+// the stack, formatted the way loggers are used to seeing it.
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// formatEntry renders an Entry the same way the old Log(level, msg)
+// implementations used to: the message, followed by any fields appended
+// as "key=value" pairs, so text/file/syslog loggers keep producing
+// readable output without having to know about JSON. This is what
+// TextFormatter uses under the hood.
+func formatEntry(e Entry) string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+
+	msg := e.Message
+	for _, k := range sortedFieldKeys(e.Fields) {
+		msg += fmt.Sprintf(" %s=%v", k, e.Fields[k])
+	}
+	return msg
+}
+
+// sortedFieldKeys returns the keys of fields in sorted order, so
+// formatters produce a stable, diffable line instead of depending on Go's
+// randomized map iteration order.
+func sortedFieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FieldLogger is a contextual logger returned by WithFields: every message
+// logged through it carries the attached fields in addition to its own,
+// and is dispatched to every registered Logger just like a plain Log
+// call.
+type FieldLogger struct {
+	fields map[string]interface{}
+}
+
+// WithFields returns a FieldLogger that merges the given fields into every
+// entry it logs, so structured loggers such as the JSON logger can pick
+// them up without the caller having to re-state them on every call.
+func WithFields(fields map[string]interface{}) *FieldLogger {
+	merged := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &FieldLogger{fields: merged}
+}
+
+// WithFields returns a new FieldLogger that carries both fl's existing
+// fields and the new ones, so context can be nested call after call.
+func (fl *FieldLogger) WithFields(fields map[string]interface{}) *FieldLogger {
+	merged := make(map[string]interface{}, len(fl.fields)+len(fields))
+	for k, v := range fl.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &FieldLogger{fields: merged}
+}
+
+// Log dispatches msg at the given level to every registered logger,
+// attaching fl's fields to the resulting Entry.
+func (fl *FieldLogger) Log(level int, msg string) {
+	debugMut.Lock()
+	defer debugMut.Unlock()
+	e := newEntry(level, msg, fl.fields)
+	for _, l := range loggers {
+		if level <= l.GetLoggerInfo().DebugLvl {
+			l.Log(e)
+		}
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+type jsonLogger struct {
+	lInfo *LoggerInfo
+	w     jsonWriter
+}
+
+// jsonWriter is the subset of io.Writer the JSON logger needs; kept as its
+// own interface so tests can swap in anything that implements Write.
+type jsonWriter interface {
+	Write(p []byte) (n int, err error)
+}
+
+// NewJSONLogger creates a Logger that writes one JSON object per entry to
+// w, with "time", "level", "caller" and "msg" keys plus whatever fields
+// were attached through WithFields. It uses JSONFormatter unless lInfo.Formatter
+// is set to something else.
+func NewJSONLogger(w jsonWriter, lInfo *LoggerInfo) Logger {
+	return &jsonLogger{lInfo: lInfo, w: w}
+}
+
+func (jl *jsonLogger) Log(e Entry) {
+	if _, err := jl.w.Write(jl.lInfo.formatter(JSONFormatter{}).Format(e)); err != nil {
+		panic(err)
+	}
+}
+
+func (jl *jsonLogger) Close() {}
+
+func (jl *jsonLogger) GetLoggerInfo() *LoggerInfo {
+	return jl.lInfo
+}
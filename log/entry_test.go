@@ -0,0 +1,54 @@
+package log
+
+import (
+	"testing"
+)
+
+type fakeLogger struct {
+	entries []Entry
+	lInfo   *LoggerInfo
+}
+
+func (f *fakeLogger) Log(e Entry)                { f.entries = append(f.entries, e) }
+func (f *fakeLogger) Close()                     {}
+func (f *fakeLogger) GetLoggerInfo() *LoggerInfo { return f.lInfo }
+
+func TestWithFieldsMerging(t *testing.T) {
+	base := WithFields(map[string]interface{}{"a": 1})
+	nested := base.WithFields(map[string]interface{}{"b": 2})
+
+	if _, ok := nested.fields["a"]; !ok {
+		t.Fatalf("expected nested logger to carry parent's fields")
+	}
+	if _, ok := nested.fields["b"]; !ok {
+		t.Fatalf("expected nested logger to carry its own fields")
+	}
+	if _, ok := base.fields["b"]; ok {
+		t.Fatalf("WithFields must not mutate the parent")
+	}
+}
+
+func TestFieldLoggerDispatch(t *testing.T) {
+	fl := &fakeLogger{lInfo: &LoggerInfo{DebugLvl: 5}}
+	key := RegisterLogger(fl)
+	defer UnregisterLogger(key)
+
+	WithFields(map[string]interface{}{"req": "abc"}).Log(lvlInfo, "hello")
+
+	if len(fl.entries) != 1 {
+		t.Fatalf("expected 1 entry logged, got %d", len(fl.entries))
+	}
+	e := fl.entries[0]
+	if e.Message != "hello" || e.Fields["req"] != "abc" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+}
+
+func TestFormatEntryAppendsSortedFields(t *testing.T) {
+	e := Entry{Message: "hi", Fields: map[string]interface{}{"b": 2, "a": 1}}
+	got := formatEntry(e)
+	want := "hi a=1 b=2"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
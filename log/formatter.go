@@ -0,0 +1,204 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Formatter renders an Entry into the bytes a sink should write. Sinks
+// (stdLogger, fileLogger, syslogLogger, the JSON logger, ...) hold a
+// Formatter rather than formatting messages themselves, so the same
+// TextFormatter/JSONFormatter/LogfmtFormatter can be reused across all of
+// them, and a caller can e.g. write JSON to a file while a console logger
+// keeps its usual colorized text.
+type Formatter interface {
+	Format(e Entry) []byte
+}
+
+// TextFormatter renders an Entry as the plain, human-oriented line the
+// std/file/syslog loggers have always produced: the message followed by
+// any fields appended as "key=value" pairs. It is the default formatter
+// for every sink except the JSON logger. Colorizing the level is left to
+// stdLogger itself, since only it knows whether its destination is
+// actually an interactive terminal.
+type TextFormatter struct{}
+
+// Format implements Formatter.
+func (TextFormatter) Format(e Entry) []byte {
+	return []byte(formatEntry(e))
+}
+
+// JSONFormatter renders an Entry as a single line of JSON with "time",
+// "level", "caller" and "msg" keys plus any fields attached through
+// WithFields. It is the default formatter used by NewJSONLogger.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(e Entry) []byte {
+	data, err := json.Marshal(jsonLine(e))
+	if err != nil {
+		// Fields is an arbitrary caller-supplied map (see WithFields): a
+		// channel, a func, or a type with a broken MarshalJSON can't be
+		// marshaled. A logging call must never be able to crash the
+		// process over what was logged, so fall back to stringifying
+		// every field instead of panicking.
+		data, err = json.Marshal(safeJSONLine(e, err))
+		if err != nil {
+			// Unreachable in practice: every value below is a plain
+			// string or int, but don't let a formatter ever panic regardless.
+			return []byte(fmt.Sprintf("{\"level\":%d,\"msg\":%q}\n", e.Level, e.Message))
+		}
+	}
+	return append(data, '\n')
+}
+
+func jsonLine(e Entry) map[string]interface{} {
+	line := make(map[string]interface{}, len(e.Fields)+4)
+	for k, v := range e.Fields {
+		line[k] = v
+	}
+	line["time"] = e.Time.Format(time.RFC3339Nano)
+	line["level"] = e.Level
+	line["caller"] = e.Caller
+	line["msg"] = e.Message
+	return line
+}
+
+// safeJSONLine rebuilds a JSON line with every field stringified, for when
+// one of them refused to marshal as-is.
+func safeJSONLine(e Entry, marshalErr error) map[string]interface{} {
+	line := make(map[string]interface{}, len(e.Fields)+5)
+	for k, v := range e.Fields {
+		line[k] = fmt.Sprintf("%v", v)
+	}
+	line["time"] = e.Time.Format(time.RFC3339Nano)
+	line["level"] = e.Level
+	line["caller"] = e.Caller
+	line["msg"] = e.Message
+	line["fields_error"] = marshalErr.Error()
+	return line
+}
+
+// GELFFormatter renders an Entry as a spec-compliant GELF 1.1 message
+// (https://go2docs.graylog.org/5-0/getting_in_log_data/gelf.html): the
+// required "version", "host", "short_message" and "timestamp" (Unix
+// seconds, as used by Graylog) keys, "level" mapped to a syslog severity
+// instead of this package's own level numbers, and every WithFields entry
+// re-keyed as a GELF "additional field" ("_"-prefixed). It is the default
+// formatter used by NewNetLogger when FramingGELFChunked is selected.
+type GELFFormatter struct {
+	// Host overrides the GELF "host" field. When empty, os.Hostname() is
+	// used, falling back to "unknown" if that fails.
+	Host string
+}
+
+// Format implements Formatter. GELF messages are self-delimiting (one
+// message per UDP datagram, or chunked by NewNetLogger), so unlike the
+// other formatters this does not append a trailing newline.
+func (g GELFFormatter) Format(e Entry) []byte {
+	line := make(map[string]interface{}, len(e.Fields)+5)
+	for k, v := range e.Fields {
+		if k == "id" {
+			// "id" is reserved by the GELF spec and must be dropped by
+			// the client, not forwarded.
+			continue
+		}
+		line["_"+k] = v
+	}
+	line["version"] = "1.1"
+	line["host"] = g.host()
+	line["short_message"] = e.Message
+	line["timestamp"] = float64(e.Time.UnixNano()) / 1e9
+	line["level"] = gelfSeverity(e.Level)
+	if e.Caller != "" {
+		line["_caller"] = e.Caller
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		// Same reasoning as JSONFormatter: never let what was logged take
+		// the process down. Re-marshal with every additional field
+		// stringified instead, keeping the required GELF keys intact.
+		for k, v := range e.Fields {
+			if k == "id" {
+				continue
+			}
+			line["_"+k] = fmt.Sprintf("%v", v)
+		}
+		data, err = json.Marshal(line)
+		if err != nil {
+			return []byte(fmt.Sprintf(`{"version":"1.1","host":%q,"short_message":%q,"timestamp":%d,"level":%d}`,
+				g.host(), e.Message, e.Time.Unix(), gelfSeverity(e.Level)))
+		}
+	}
+	return data
+}
+
+func (g GELFFormatter) host() string {
+	if g.Host != "" {
+		return g.Host
+	}
+	h, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return h
+}
+
+// gelfSeverity maps this package's level numbers onto the syslog
+// severities (0 emergency .. 7 debug) that GELF's "level" field expects.
+func gelfSeverity(level int) int {
+	switch level {
+	case lvlPanic, lvlFatal:
+		return 2 // critical
+	case lvlError:
+		return 3 // error
+	case lvlWarning:
+		return 4 // warning
+	case lvlInfo, lvlPrint:
+		return 6 // informational
+	default:
+		return 7 // debug: the numbered DebugLvl levels and anything else
+	}
+}
+
+// LogfmtFormatter renders an Entry using the logfmt convention (as used
+// by tools like Heroku's log pipeline and go-kit): space-separated
+// key=value pairs, with values quoted when they contain whitespace.
+type LogfmtFormatter struct{}
+
+// Format implements Formatter.
+func (LogfmtFormatter) Format(e Entry) []byte {
+	var b bytes.Buffer
+	writeLogfmtPair(&b, "time", e.Time.Format(time.RFC3339Nano))
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, "level", e.Level)
+	if e.Caller != "" {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, "caller", e.Caller)
+	}
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, "msg", e.Message)
+
+	for _, k := range sortedFieldKeys(e.Fields) {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, k, e.Fields[k])
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key string, val interface{}) {
+	b.WriteString(key)
+	b.WriteByte('=')
+	s := fmt.Sprintf("%v", val)
+	if strings.ContainsAny(s, " \t\r\n\"=") {
+		b.WriteString(fmt.Sprintf("%q", s))
+	} else {
+		b.WriteString(s)
+	}
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextFormatterAppendsFields(t *testing.T) {
+	e := Entry{Message: "hi", Fields: map[string]interface{}{"k": "v"}}
+	got := string(TextFormatter{}.Format(e))
+	if got != "hi k=v" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestJSONFormatterRoundTrips(t *testing.T) {
+	e := Entry{
+		Level:   1,
+		Time:    time.Unix(0, 0),
+		Caller:  "x.go:1",
+		Message: "hi",
+		Fields:  map[string]interface{}{"k": "v"},
+	}
+	data := JSONFormatter{}.Format(e)
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatalf("output is not valid JSON: %v (data: %s)", err, data)
+	}
+	if line["msg"] != "hi" || line["k"] != "v" {
+		t.Fatalf("unexpected line: %+v", line)
+	}
+}
+
+func TestJSONFormatterDoesNotPanicOnUnmarshalableField(t *testing.T) {
+	e := Entry{
+		Message: "hi",
+		Fields:  map[string]interface{}{"bad": make(chan int)},
+	}
+
+	var data []byte
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Format must not panic, got: %v", r)
+			}
+		}()
+		data = JSONFormatter{}.Format(e)
+	}()
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatalf("fallback output is not valid JSON: %v (data: %s)", err, data)
+	}
+	if line["msg"] != "hi" {
+		t.Fatalf("expected the message to survive the fallback, got: %+v", line)
+	}
+}
+
+func TestLogfmtFormatterQuotesValuesWithSpaces(t *testing.T) {
+	e := Entry{Message: "hello world"}
+	got := string(LogfmtFormatter{}.Format(e))
+	if !strings.Contains(got, `msg="hello world"`) {
+		t.Fatalf("expected quoted msg, got %q", got)
+	}
+}
+
+func TestGELFFormatterMapsRequiredFields(t *testing.T) {
+	e := Entry{
+		Level:   lvlError,
+		Time:    time.Unix(100, 0),
+		Message: "boom",
+		Fields:  map[string]interface{}{"user": "alice", "id": "should-be-dropped"},
+	}
+	data := GELFFormatter{Host: "myhost"}.Format(e)
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatalf("output is not valid JSON: %v (data: %s)", err, data)
+	}
+	if line["version"] != "1.1" || line["host"] != "myhost" || line["short_message"] != "boom" {
+		t.Fatalf("unexpected line: %+v", line)
+	}
+	if line["level"] != float64(3) { // lvlError -> syslog "error"
+		t.Fatalf("expected severity 3, got %v", line["level"])
+	}
+	if _, ok := line["_user"]; !ok {
+		t.Fatalf("expected _user additional field, got %+v", line)
+	}
+	if _, ok := line["id"]; ok {
+		t.Fatalf(`the reserved "id" field must be dropped`)
+	}
+}
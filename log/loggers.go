@@ -2,8 +2,8 @@ package log
 
 import (
 	"fmt"
-	"log/syslog"
 	"os"
+	"sync"
 
 	"github.com/daviddengcn/go-colortext"
 )
@@ -23,15 +23,41 @@ type LoggerInfo struct {
 	// If 'useColors' is true, logs will be colored (defaults to monochrome
 	// output). It also controls padding, since colorful output is higly
 	// correlated with humans who like their log lines padded.
+	//
+	// Deprecated: set Color to ColorOn instead. UseColors is still honored
+	// for backward compatibility and is equivalent to Color: ColorOn.
 	UseColors bool
+	// Color controls whether stdLogger emits ANSI color escapes. ColorAuto
+	// (the zero value) colors a line only when the sink it is written to
+	// (stdout or stderr) is detected to be an interactive terminal.
+	Color ColorMode
 	// If 'padding' is true, it will nicely pad the line that is written.
 	Padding bool
+	// ColorHeaderOnly, when colors are enabled, restricts coloring to the
+	// leading level token of the line and leaves the rest of the message
+	// in the default color.
+	ColorHeaderOnly bool
+	// Formatter renders each Entry into the bytes a sink writes. When nil,
+	// a sink falls back to its own default (TextFormatter for
+	// std/file/syslog, JSONFormatter for the JSON logger).
+	Formatter Formatter
+}
+
+// formatter returns li.Formatter, or fallback if none was set.
+func (li *LoggerInfo) formatter(fallback Formatter) Formatter {
+	if li.Formatter != nil {
+		return li.Formatter
+	}
+	return fallback
 }
 
 // Logger is the interface that specifies how loggers
-// will receive and display messages.
+// will receive and display messages. Log receives a fully-populated Entry
+// rather than a pre-formatted string so that loggers which care about
+// structure (e.g. the JSON logger) can access the level, time, caller and
+// fields directly instead of having to re-parse a rendered line.
 type Logger interface {
-	Log(level int, msg string)
+	Log(e Entry)
 	Close()
 	GetLoggerInfo() *LoggerInfo
 }
@@ -43,6 +69,9 @@ const (
 	DefaultStdShowTime = false
 	// DefaultStdUseColors is the default value for 'useColors' for the standard logger
 	DefaultStdUseColors = false
+	// DefaultStdColor is the default value for 'Color' for the standard
+	// logger: auto-detect per sink rather than forcing colors on or off.
+	DefaultStdColor = ColorAuto
 	// DefaultStdPadding is the default value for 'padding' for the standard logger
 	DefaultStdPadding = true
 )
@@ -78,17 +107,42 @@ func UnregisterLogger(key int) {
 }
 
 type fileLogger struct {
-	lInfo *LoggerInfo
-	file  *os.File
+	lInfo     *LoggerInfo
+	mut       sync.Mutex
+	file      *os.File
+	path      string
+	append    bool
+	reopenKey int
 }
 
-func (fl *fileLogger) Log(level int, msg string) {
-	if _, err := fl.file.WriteString(msg); err != nil {
+func (fl *fileLogger) Log(e Entry) {
+	data := fl.lInfo.formatter(TextFormatter{}).Format(e)
+	fl.mut.Lock()
+	defer fl.mut.Unlock()
+	if _, err := fl.file.Write(data); err != nil {
 		panic(err)
 	}
 }
 
+// Reopen closes the currently open file and re-opens fl.path, picking up
+// the file a log rotator put in its place. It holds the same mutex as
+// Log, so no message is lost or interleaved across the swap.
+func (fl *fileLogger) Reopen() error {
+	fl.mut.Lock()
+	defer fl.mut.Unlock()
+	newFile, err := openLogFile(fl.path, fl.append)
+	if err != nil {
+		return err
+	}
+	old := fl.file
+	fl.file = newFile
+	return old.Close()
+}
+
 func (fl *fileLogger) Close() {
+	unregisterReopenable(fl.reopenKey)
+	fl.mut.Lock()
+	defer fl.mut.Unlock()
 	fl.file.Close()
 }
 
@@ -96,62 +150,70 @@ func (fl *fileLogger) GetLoggerInfo() *LoggerInfo {
 	return fl.lInfo
 }
 
-// NewFileLogger creates a logger that writes into the file with
-// the given path and is using the given LoggerInfo.
-// It returns the logger.
-func NewFileLogger(lInfo *LoggerInfo, path string) (Logger, error) {
-	// Override file if it already exists.
-	file, err := os.Create(path)
-	if err != nil {
-		return nil, err
-	}
-	return &fileLogger{
-		lInfo: lInfo,
-		file:  file,
-	}, nil
-}
-
-type syslogLogger struct {
-	lInfo  *LoggerInfo
-	writer *syslog.Writer
+// FileOptions configures how NewFileLoggerWithOptions opens its file.
+type FileOptions struct {
+	// Append, when true, opens the file in append mode instead of
+	// truncating it. This is what you want when cooperating with an
+	// external log rotator such as logrotate: the rotator renames the
+	// current file away and the process keeps (or, after Reopen, starts)
+	// appending to the path again.
+	Append bool
 }
 
-func (sl *syslogLogger) Log(level int, msg string) {
-	_, err := sl.writer.Write([]byte(msg))
-	if err != nil {
-		panic(err)
+func openLogFile(path string, append bool) (*os.File, error) {
+	if append {
+		return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
 	}
+	return os.Create(path)
 }
 
-func (sl *syslogLogger) Close() {
-	sl.writer.Close()
-}
-
-func (sl *syslogLogger) GetLoggerInfo() *LoggerInfo {
-	return sl.lInfo
+// NewFileLogger creates a logger that writes into the file with
+// the given path and is using the given LoggerInfo. The file is truncated
+// if it already exists; use NewFileLoggerWithOptions to append instead.
+// It returns the logger.
+func NewFileLogger(lInfo *LoggerInfo, path string) (Logger, error) {
+	return NewFileLoggerWithOptions(lInfo, path, FileOptions{})
 }
 
-// NewSyslogLogger creates a logger that writes into syslog with
-// the given priority and tag, and is using the given LoggerInfo (without the
-// Logger).
+// NewFileLoggerWithOptions creates a logger that writes into the file with
+// the given path and is using the given LoggerInfo and FileOptions. The
+// returned logger is registered so that it is reopened whenever the
+// process receives a SIGHUP or ReopenAll is called explicitly.
 // It returns the logger.
-func NewSyslogLogger(lInfo *LoggerInfo, priority syslog.Priority, tag string) (Logger, error) {
-	writer, err := syslog.New(priority, tag)
+func NewFileLoggerWithOptions(lInfo *LoggerInfo, path string, opts FileOptions) (Logger, error) {
+	file, err := openLogFile(path, opts.Append)
 	if err != nil {
 		return nil, err
 	}
-	return &syslogLogger{
+	fl := &fileLogger{
 		lInfo:  lInfo,
-		writer: writer,
-	}, nil
+		file:   file,
+		path:   path,
+		append: opts.Append,
+	}
+	fl.reopenKey = registerReopenable(fl)
+	return fl, nil
 }
 
 type stdLogger struct {
 	lInfo *LoggerInfo
 }
 
-func (sl *stdLogger) Log(lvl int, msg string) {
-	if sl.lInfo.UseColors {
+func (sl *stdLogger) Log(e Entry) {
+	lvl := e.Level
+	msg := string(sl.lInfo.formatter(TextFormatter{}).Format(e))
+
+	isErrStream := lvl < lvlInfo
+	w := stdOut
+	f := os.Stdout
+	if isErrStream {
+		w = stdErr
+		f = os.Stderr
+	}
+
+	doColor := sl.lInfo.colorEnabled(f)
+
+	if doColor {
 		bright := lvl < 0
 		lvlAbs := lvl
 		if bright {
@@ -181,13 +243,16 @@ func (sl *stdLogger) Log(lvl int, msg string) {
 		}
 	}
 
-	if lvl < lvlInfo {
-		fmt.Fprint(stdErr, msg)
+	if doColor && sl.lInfo.ColorHeaderOnly {
+		header, rest := splitHeader(msg)
+		fmt.Fprint(w, header)
+		ct.ResetColor()
+		fmt.Fprint(w, rest)
 	} else {
-		fmt.Fprint(stdOut, msg)
+		fmt.Fprint(w, msg)
 	}
 
-	if sl.lInfo.UseColors {
+	if doColor && !sl.lInfo.ColorHeaderOnly {
 		ct.ResetColor()
 	}
 }
@@ -204,6 +269,7 @@ func newStdLogger() (Logger, error) {
 	lInfo := &LoggerInfo{
 		DebugLvl:  DefaultStdDebugLvl,
 		UseColors: DefaultStdUseColors,
+		Color:     DefaultStdColor,
 		ShowTime:  DefaultStdShowTime,
 		Padding:   DefaultStdPadding,
 	}
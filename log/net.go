@@ -0,0 +1,317 @@
+package log
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+)
+
+// FramingMode selects how NewNetLogger delimits messages on the wire.
+type FramingMode int
+
+const (
+	// FramingNewline appends a newline after each formatted entry, the
+	// newline-delimited-JSON convention understood by Logstash, Fluentd
+	// and Vector.
+	FramingNewline FramingMode = iota
+	// FramingGELFChunked splits each message into GELF chunks, for
+	// shipping to a Graylog collector over UDP.
+	FramingGELFChunked
+)
+
+const (
+	// DefaultNetBufferSize is the default number of formatted entries
+	// NewNetLogger queues in memory while waiting for the collector.
+	DefaultNetBufferSize = 1024
+	// DefaultNetDialTimeout is the default timeout for a single (re)connect
+	// attempt.
+	DefaultNetDialTimeout = 5 * time.Second
+	// DefaultNetMaxBackoff caps the exponential backoff between reconnect
+	// attempts.
+	DefaultNetMaxBackoff = 30 * time.Second
+	// DefaultNetCloseTimeout is the default time Close waits for the
+	// buffer to drain before giving up.
+	DefaultNetCloseTimeout = 2 * time.Second
+)
+
+// gelfChunkSize is the maximum UDP payload Graylog expects per chunk,
+// leaving room for the 12-byte chunk header.
+const gelfChunkSize = 8154
+
+// NetOptions configures NewNetLogger.
+type NetOptions struct {
+	// BufferSize is how many formatted entries may queue in memory while
+	// waiting to be flushed to the network. Once full, the oldest queued
+	// entry is dropped to make room for the newest one, so a stalled
+	// collector degrades the logger instead of blocking the caller.
+	// Zero means DefaultNetBufferSize.
+	BufferSize int
+	// Framing selects how messages are delimited on the wire. Zero means
+	// FramingNewline.
+	Framing FramingMode
+	// DialTimeout bounds how long a single (re)connect attempt may take.
+	// Zero means DefaultNetDialTimeout.
+	DialTimeout time.Duration
+	// MaxBackoff caps the exponential backoff between reconnect attempts.
+	// Zero means DefaultNetMaxBackoff.
+	MaxBackoff time.Duration
+	// CloseTimeout bounds how long Close waits for the buffer to drain
+	// before giving up. Zero means DefaultNetCloseTimeout.
+	CloseTimeout time.Duration
+	// TLSConfig, when set, makes NewNetLogger dial with TLS instead of a
+	// plain net.Dial. Only meaningful for stream networks such as "tcp".
+	TLSConfig *tls.Config
+}
+
+func (o NetOptions) bufferSize() int {
+	if o.BufferSize > 0 {
+		return o.BufferSize
+	}
+	return DefaultNetBufferSize
+}
+
+func (o NetOptions) dialTimeout() time.Duration {
+	if o.DialTimeout > 0 {
+		return o.DialTimeout
+	}
+	return DefaultNetDialTimeout
+}
+
+func (o NetOptions) maxBackoff() time.Duration {
+	if o.MaxBackoff > 0 {
+		return o.MaxBackoff
+	}
+	return DefaultNetMaxBackoff
+}
+
+func (o NetOptions) closeTimeout() time.Duration {
+	if o.CloseTimeout > 0 {
+		return o.CloseTimeout
+	}
+	return DefaultNetCloseTimeout
+}
+
+// netLogger ships formatted entries to a remote collector over TCP, UDP or
+// TLS. It complements syslogLogger for environments where syslog isn't
+// available or JSON-over-TCP is preferred, e.g. Logstash, Fluentd, Vector
+// or a Graylog GELF endpoint.
+type netLogger struct {
+	lInfo   *LoggerInfo
+	network string
+	addr    string
+	opts    NetOptions
+
+	mut    sync.Mutex
+	buf    [][]byte
+	notify chan struct{}
+
+	closeOnce sync.Once
+	closing   chan struct{}
+	closed    chan struct{}
+}
+
+// NewNetLogger creates a Logger that ships formatted entries to addr over
+// network ("tcp", "udp", or "tcp" combined with opts.TLSConfig for TLS).
+// Entries are queued in a bounded buffer and flushed by a background
+// goroutine that reconnects with exponential backoff if the collector is
+// unreachable; Close drains the buffer, waiting at most
+// opts.CloseTimeout. The default Formatter is JSONFormatter, or
+// GELFFormatter when opts.Framing is FramingGELFChunked, matching what
+// those collectors expect.
+func NewNetLogger(lInfo *LoggerInfo, network, addr string, opts NetOptions) Logger {
+	nl := &netLogger{
+		lInfo:   lInfo,
+		network: network,
+		addr:    addr,
+		opts:    opts,
+		notify:  make(chan struct{}, 1),
+		closing: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	go nl.run()
+	return nl
+}
+
+func (nl *netLogger) Log(e Entry) {
+	if nl.opts.Framing == FramingGELFChunked {
+		nl.enqueueChunked(nl.lInfo.formatter(GELFFormatter{}).Format(e))
+		return
+	}
+	data := nl.lInfo.formatter(JSONFormatter{}).Format(e)
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	nl.enqueue(data)
+}
+
+// enqueue appends data to the buffer, dropping the oldest entry if the
+// buffer is already at capacity.
+func (nl *netLogger) enqueue(data []byte) {
+	nl.mut.Lock()
+	if len(nl.buf) >= nl.opts.bufferSize() {
+		nl.buf = nl.buf[1:]
+	}
+	nl.buf = append(nl.buf, data)
+	nl.mut.Unlock()
+
+	select {
+	case nl.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (nl *netLogger) enqueueChunked(data []byte) {
+	if len(data) <= gelfChunkSize {
+		nl.enqueue(data)
+		return
+	}
+
+	msgID := make([]byte, 8)
+	if _, err := rand.Read(msgID); err != nil {
+		panic(err)
+	}
+
+	total := (len(data) + gelfChunkSize - 1) / gelfChunkSize
+	for seq := 0; seq < total; seq++ {
+		start := seq * gelfChunkSize
+		end := start + gelfChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk := make([]byte, 0, 12+end-start)
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, msgID...)
+		chunk = append(chunk, byte(seq), byte(total))
+		chunk = append(chunk, data[start:end]...)
+		nl.enqueue(chunk)
+	}
+}
+
+// run owns the connection and drains the buffer onto it, reconnecting
+// with exponential backoff whenever dialing or writing fails.
+func (nl *netLogger) run() {
+	defer close(nl.closed)
+
+	var conn net.Conn
+	backoff := nl.opts.dialTimeout()
+
+	closeConn := func() {
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+	}
+	defer closeConn()
+
+	for {
+		// If there is data waiting and we failed to send it last time
+		// round, wake ourselves up after the current backoff even if no
+		// new message arrives, instead of waiting indefinitely for one.
+		var retry <-chan time.Time
+		if _, pending := nl.peek(); pending {
+			retry = time.After(backoff)
+		}
+
+		select {
+		case <-nl.closing:
+			nl.flush(&conn)
+			return
+		case <-nl.notify:
+		case <-retry:
+		}
+
+		for {
+			data, ok := nl.peek()
+			if !ok {
+				backoff = nl.opts.dialTimeout()
+				break
+			}
+
+			if conn == nil {
+				c, err := nl.dial()
+				if err != nil {
+					backoff *= 2
+					if backoff > nl.opts.maxBackoff() {
+						backoff = nl.opts.maxBackoff()
+					}
+					break
+				}
+				conn = c
+			}
+
+			if _, err := conn.Write(data); err != nil {
+				closeConn()
+				break
+			}
+			nl.pop()
+		}
+	}
+}
+
+func (nl *netLogger) dial() (net.Conn, error) {
+	if nl.opts.TLSConfig != nil {
+		return tls.DialWithDialer(&net.Dialer{Timeout: nl.opts.dialTimeout()}, nl.network, nl.addr, nl.opts.TLSConfig)
+	}
+	return net.DialTimeout(nl.network, nl.addr, nl.opts.dialTimeout())
+}
+
+// peek returns the oldest queued message without removing it.
+func (nl *netLogger) peek() ([]byte, bool) {
+	nl.mut.Lock()
+	defer nl.mut.Unlock()
+	if len(nl.buf) == 0 {
+		return nil, false
+	}
+	return nl.buf[0], true
+}
+
+// pop removes the oldest queued message, once it has been written.
+func (nl *netLogger) pop() {
+	nl.mut.Lock()
+	defer nl.mut.Unlock()
+	if len(nl.buf) > 0 {
+		nl.buf = nl.buf[1:]
+	}
+}
+
+// flush makes a best-effort attempt to write every remaining buffered
+// message before Close gives up.
+func (nl *netLogger) flush(conn *net.Conn) {
+	for {
+		data, ok := nl.peek()
+		if !ok {
+			return
+		}
+		if *conn == nil {
+			c, err := nl.dial()
+			if err != nil {
+				return
+			}
+			*conn = c
+		}
+		if _, err := (*conn).Write(data); err != nil {
+			return
+		}
+		nl.pop()
+	}
+}
+
+// Close signals the background goroutine to drain the buffer and waits
+// for it to finish, up to opts.CloseTimeout. It is safe to call more than
+// once, unlike closing nl.closing directly would be.
+func (nl *netLogger) Close() {
+	nl.closeOnce.Do(func() {
+		close(nl.closing)
+		select {
+		case <-nl.closed:
+		case <-time.After(nl.opts.closeTimeout()):
+		}
+	})
+}
+
+func (nl *netLogger) GetLoggerInfo() *LoggerInfo {
+	return nl.lInfo
+}
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNetLoggerShipsJSONOverTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		if scanner.Scan() {
+			received <- scanner.Text()
+		}
+	}()
+
+	nl := NewNetLogger(&LoggerInfo{}, "tcp", ln.Addr().String(), NetOptions{
+		DialTimeout: time.Second,
+	})
+	defer nl.Close()
+
+	nl.Log(Entry{Message: "hello"})
+
+	select {
+	case line := <-received:
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("expected valid JSON line, got %q: %v", line, err)
+		}
+		if decoded["msg"] != "hello" {
+			t.Fatalf("unexpected line: %+v", decoded)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the collector to receive a message")
+	}
+}
+
+func TestNetLoggerCloseIsIdempotent(t *testing.T) {
+	nl := NewNetLogger(&LoggerInfo{}, "tcp", "127.0.0.1:1", NetOptions{
+		DialTimeout:  10 * time.Millisecond,
+		CloseTimeout: 50 * time.Millisecond,
+	})
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Close must be safe to call twice, got: %v", r)
+		}
+	}()
+	nl.Close()
+	nl.Close()
+}
+
+func TestEnqueueChunkedSplitsOversizeMessages(t *testing.T) {
+	nl := &netLogger{
+		notify: make(chan struct{}, 1),
+	}
+	big := make([]byte, gelfChunkSize*2+10)
+	for i := range big {
+		big[i] = 'x'
+	}
+
+	nl.enqueueChunked(big)
+
+	if len(nl.buf) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(nl.buf))
+	}
+	for i, chunk := range nl.buf {
+		if chunk[0] != 0x1e || chunk[1] != 0x0f {
+			t.Fatalf("chunk %d missing GELF magic bytes", i)
+		}
+		if int(chunk[11]) != 3 {
+			t.Fatalf("chunk %d has wrong total count byte: %d", i, chunk[11])
+		}
+	}
+}
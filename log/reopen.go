@@ -0,0 +1,76 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	reopenMut      sync.Mutex
+	reopenable     = make(map[int]*fileLogger)
+	reopenCounter  int
+	sighupListener sync.Once
+)
+
+// registerReopenable records fl so that ReopenAll (and SIGHUP) picks it up,
+// and makes sure the SIGHUP listener goroutine is running. It returns the
+// key to pass to unregisterReopenable.
+func registerReopenable(fl *fileLogger) int {
+	sighupListener.Do(listenForSIGHUP)
+
+	reopenMut.Lock()
+	defer reopenMut.Unlock()
+	key := reopenCounter
+	reopenable[key] = fl
+	reopenCounter++
+	return key
+}
+
+// unregisterReopenable removes fl so a closed fileLogger isn't reopened
+// after its file is gone.
+func unregisterReopenable(key int) {
+	reopenMut.Lock()
+	defer reopenMut.Unlock()
+	delete(reopenable, key)
+}
+
+// ReopenAll reopens every fileLogger created with NewFileLogger or
+// NewFileLoggerWithOptions that is still open. It is called automatically
+// on SIGHUP, but is exported so a program can trigger it itself, e.g. in
+// response to its own rotation signal.
+//
+// A logger that fails to reopen (the rotator hasn't finished renaming the
+// file yet, a permissions error, a full disk, ...) doesn't stop the rest
+// from being reopened: this runs from the unrecovered SIGHUP goroutine, so
+// panicking here would crash the whole process over what is, at worst, one
+// stale log file.
+func ReopenAll() {
+	reopenMut.Lock()
+	toReopen := make([]*fileLogger, 0, len(reopenable))
+	for _, fl := range reopenable {
+		toReopen = append(toReopen, fl)
+	}
+	reopenMut.Unlock()
+
+	for _, fl := range toReopen {
+		if err := fl.Reopen(); err != nil {
+			fmt.Fprintf(os.Stderr, "log: failed to reopen %s: %v\n", fl.path, err)
+		}
+	}
+}
+
+// listenForSIGHUP starts a goroutine that calls ReopenAll every time the
+// process receives a SIGHUP, which is the signal a log rotator like
+// logrotate sends after renaming the file out from under us.
+func listenForSIGHUP() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		for range c {
+			ReopenAll()
+		}
+	}()
+}
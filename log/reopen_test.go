@@ -0,0 +1,93 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileLoggerReopenPicksUpRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	l, err := NewFileLogger(&LoggerInfo{DebugLvl: 5}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.Log(Entry{Message: "before rotate\n"})
+
+	rotated := path + ".1"
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatal(err)
+	}
+
+	fl := l.(*fileLogger)
+	if err := fl.Reopen(); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+
+	l.Log(Entry{Message: "after rotate\n"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "after rotate\n" {
+		t.Fatalf("got %q", data)
+	}
+
+	rotatedData, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rotatedData) != "before rotate\n" {
+		t.Fatalf("got %q", rotatedData)
+	}
+}
+
+func TestReopenAllSkipsFailingLoggerWithoutPanicking(t *testing.T) {
+	dir := t.TempDir()
+	goodPath := filepath.Join(dir, "good.log")
+	badPath := filepath.Join(dir, "bad.log")
+
+	good, err := NewFileLogger(&LoggerInfo{DebugLvl: 5}, goodPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer good.Close()
+
+	bad, err := NewFileLogger(&LoggerInfo{DebugLvl: 5}, badPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bad.Close()
+
+	// Make the bad logger's path impossible to reopen by replacing it with
+	// a directory of the same name, simulating a rotator race / perm error.
+	if err := os.Remove(badPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(badPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("ReopenAll must not panic, got: %v", r)
+			}
+		}()
+		ReopenAll()
+	}()
+
+	good.Log(Entry{Message: "still works\n"})
+	data, err := os.ReadFile(goodPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "still works\n" {
+		t.Fatalf("got %q", data)
+	}
+}
@@ -0,0 +1,219 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SampleOptions configures the throttling policy used by a sampled
+// Logger: the first First occurrences of a given (level, message) pair
+// within Interval pass straight through, then only 1 in Thereafter does.
+type SampleOptions struct {
+	// First is the number of occurrences of a given (level, message) pair
+	// that are let through unsampled before thinning kicks in.
+	First int
+	// Thereafter, once First has been exceeded within the current
+	// Interval, only 1 in every Thereafter occurrences is passed through.
+	// Zero disables the "1 in M" pass-through: nothing more gets through
+	// once First is reached.
+	Thereafter int
+	// Interval is the window after which a message's counters reset and
+	// any pending suppression summary is flushed. It also sets how often
+	// the background flush loop checks for expired windows, so a burst
+	// that stops without a further matching Log call still gets its
+	// summary emitted.
+	Interval time.Duration
+	// PassLevels lists levels that are never sampled, e.g. lvlError,
+	// lvlFatal, lvlPanic, which should always reach the inner logger.
+	PassLevels []int
+}
+
+// DefaultSampleOptions lets the first 10 occurrences of a message through
+// every 10 seconds, then 1 in 100 after that.
+var DefaultSampleOptions = SampleOptions{
+	First:      10,
+	Thereafter: 100,
+	Interval:   10 * time.Second,
+}
+
+func (o SampleOptions) interval() time.Duration {
+	if o.Interval > 0 {
+		return o.Interval
+	}
+	return DefaultSampleOptions.Interval
+}
+
+type sampleBucket struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+	// level, message and caller are snapshotted from the Entry that
+	// started the window, so a summary can still be built for a bucket
+	// that the background flush loop expires, not just one a matching
+	// Log call happens to revisit.
+	level   int
+	message string
+	caller  string
+}
+
+type sampledLogger struct {
+	inner   Logger
+	opts    SampleOptions
+	mut     sync.Mutex
+	buckets map[string]*sampleBucket
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewSampledLogger wraps inner so that bursts of duplicate messages are
+// thinned instead of flooding the sink. It is meant to sit between a hot
+// loop that might call Warn/Info a huge number of times and whatever
+// RegisterLogger output would otherwise have to absorb that, e.g. syslog.
+// When a burst is thinned, a "... suppressed N similar messages in last
+// Xs" line is emitted to inner: either when the window rolls over on the
+// next matching Log call, or, if no further matching message arrives, by
+// a background loop that ticks every opts.Interval. Close flushes any
+// still-pending summary before closing inner, so a burst that is in
+// progress when the program shuts down isn't silently dropped.
+func NewSampledLogger(inner Logger, opts SampleOptions) Logger {
+	s := &sampledLogger{
+		inner:   inner,
+		opts:    opts,
+		buckets: make(map[string]*sampleBucket),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.flushLoop()
+	return s
+}
+
+func (s *sampledLogger) passLevel(level int) bool {
+	for _, l := range s.opts.PassLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleKey identifies a message-template: in the absence of a real
+// template language, the level plus the rendered message stands in for
+// it, which still collapses the common case of a hot loop logging the
+// exact same string over and over.
+func sampleKey(e Entry) string {
+	return fmt.Sprintf("%d|%s", e.Level, e.Message)
+}
+
+func (s *sampledLogger) Log(e Entry) {
+	if s.passLevel(e.Level) {
+		s.inner.Log(e)
+		return
+	}
+
+	key := sampleKey(e)
+
+	s.mut.Lock()
+	b, ok := s.buckets[key]
+	var summary *Entry
+	if !ok || e.Time.Sub(b.windowStart) >= s.opts.interval() {
+		if ok && b.suppressed > 0 {
+			summary = suppressedSummary(e.Time, b)
+		}
+		b = &sampleBucket{windowStart: e.Time, level: e.Level, message: e.Message, caller: e.Caller}
+		s.buckets[key] = b
+	}
+	b.count++
+	pass := b.count <= s.opts.First ||
+		(s.opts.Thereafter > 0 && (b.count-s.opts.First)%s.opts.Thereafter == 0)
+	if !pass {
+		b.suppressed++
+	}
+	s.mut.Unlock()
+
+	if summary != nil {
+		s.inner.Log(*summary)
+	}
+	if pass {
+		s.inner.Log(e)
+	}
+}
+
+// suppressedSummary builds the "... suppressed N similar messages" Entry
+// for b as of now, which is either the time of the Log call that rolled
+// the window over or, for the background flush loop, the time the window
+// was found to have expired.
+func suppressedSummary(now time.Time, b *sampleBucket) *Entry {
+	elapsed := now.Sub(b.windowStart).Round(time.Second)
+	return &Entry{
+		Level:   b.level,
+		Time:    now,
+		Caller:  b.caller,
+		Message: fmt.Sprintf("... suppressed %d similar messages in last %s", b.suppressed, elapsed),
+	}
+}
+
+// flushLoop periodically expires buckets whose window has elapsed,
+// emitting a pending summary for each one, so a burst that simply stops
+// (rather than being followed by another matching Log call) still gets
+// its summary flushed instead of being silently lost.
+func (s *sampledLogger) flushLoop() {
+	defer close(s.done)
+
+	t := time.NewTicker(s.opts.interval())
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			s.flushPending(true)
+			return
+		case now := <-t.C:
+			s.flushPendingAt(now, false)
+		}
+	}
+}
+
+// flushPending flushes every bucket with a pending suppression count,
+// used by Close where nothing should be left behind regardless of
+// whether its window has technically expired yet.
+func (s *sampledLogger) flushPending(all bool) {
+	s.flushPendingAt(time.Now(), all)
+}
+
+func (s *sampledLogger) flushPendingAt(now time.Time, all bool) {
+	var summaries []Entry
+
+	s.mut.Lock()
+	for key, b := range s.buckets {
+		if !all && now.Sub(b.windowStart) < s.opts.interval() {
+			continue
+		}
+		if b.suppressed > 0 {
+			summaries = append(summaries, *suppressedSummary(now, b))
+		}
+		delete(s.buckets, key)
+	}
+	s.mut.Unlock()
+
+	for _, e := range summaries {
+		s.inner.Log(e)
+	}
+}
+
+// Close stops the background flush loop, flushes any still-pending
+// suppression summaries, and closes inner. It is safe to call more than
+// once.
+func (s *sampledLogger) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.inner.Close()
+	})
+}
+
+func (s *sampledLogger) GetLoggerInfo() *LoggerInfo {
+	return s.inner.GetLoggerInfo()
+}
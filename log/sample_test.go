@@ -0,0 +1,136 @@
+package log
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type collectingLogger struct {
+	mut     sync.Mutex
+	entries []Entry
+	lInfo   *LoggerInfo
+}
+
+func (c *collectingLogger) Log(e Entry) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.entries = append(c.entries, e)
+}
+func (c *collectingLogger) Close()                     {}
+func (c *collectingLogger) GetLoggerInfo() *LoggerInfo { return c.lInfo }
+
+func (c *collectingLogger) snapshot() []Entry {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	out := make([]Entry, len(c.entries))
+	copy(out, c.entries)
+	return out
+}
+
+func TestSampledLoggerThinsBursts(t *testing.T) {
+	inner := &collectingLogger{lInfo: &LoggerInfo{}}
+	sampled := NewSampledLogger(inner, SampleOptions{
+		First:      2,
+		Thereafter: 3,
+		Interval:   time.Hour,
+	})
+	defer sampled.Close()
+
+	now := time.Now()
+	for i := 0; i < 8; i++ {
+		sampled.Log(Entry{Level: 1, Message: "flood", Time: now})
+	}
+
+	passed := 0
+	for _, e := range inner.snapshot() {
+		if e.Message == "flood" {
+			passed++
+		}
+	}
+	// occurrences 1,2 pass (First); then 1 in 3 after that: occurrences 5
+	// and 8 pass too.
+	if passed != 4 {
+		t.Fatalf("expected 4 messages to pass through, got %d", passed)
+	}
+}
+
+func TestSampledLoggerPassLevelsBypass(t *testing.T) {
+	inner := &collectingLogger{lInfo: &LoggerInfo{}}
+	sampled := NewSampledLogger(inner, SampleOptions{
+		First:      1,
+		Thereafter: 100,
+		Interval:   time.Hour,
+		PassLevels: []int{9},
+	})
+	defer sampled.Close()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		sampled.Log(Entry{Level: 9, Message: "critical", Time: now})
+	}
+
+	passed := 0
+	for _, e := range inner.snapshot() {
+		if e.Message == "critical" {
+			passed++
+		}
+	}
+	if passed != 5 {
+		t.Fatalf("PassLevels should bypass sampling entirely, got %d passed", passed)
+	}
+}
+
+func TestSampledLoggerFlushesOnClose(t *testing.T) {
+	inner := &collectingLogger{lInfo: &LoggerInfo{}}
+	sampled := NewSampledLogger(inner, SampleOptions{
+		First:      1,
+		Thereafter: 0,
+		Interval:   time.Hour,
+	})
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		sampled.Log(Entry{Level: 1, Message: "flood", Time: now})
+	}
+
+	sampled.Close()
+
+	found := false
+	for _, e := range inner.snapshot() {
+		if e.Message != "flood" && e.Message != "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected Close to flush a pending suppression summary, entries: %+v", inner.snapshot())
+	}
+}
+
+func TestSampledLoggerFlushesPeriodically(t *testing.T) {
+	inner := &collectingLogger{lInfo: &LoggerInfo{}}
+	sampled := NewSampledLogger(inner, SampleOptions{
+		First:      1,
+		Thereafter: 0,
+		Interval:   50 * time.Millisecond,
+	})
+	defer sampled.Close()
+
+	now := time.Now()
+	sampled.Log(Entry{Level: 1, Message: "flood", Time: now})
+	sampled.Log(Entry{Level: 1, Message: "flood", Time: now})
+
+	deadline := time.After(2 * time.Second)
+	for {
+		for _, e := range inner.snapshot() {
+			if e.Message != "flood" {
+				return
+			}
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("expected a suppression summary to be flushed in the background")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+// +build !windows
+
+package log
+
+import "log/syslog"
+
+// syslog is POSIX-only: the stdlib's log/syslog doesn't implement Dial/New
+// on Windows, so this sink is built only on platforms where it works. See
+// NewSyslogLogger in syslog_windows.go for the Windows stand-in.
+type syslogLogger struct {
+	lInfo  *LoggerInfo
+	writer *syslog.Writer
+}
+
+func (sl *syslogLogger) Log(e Entry) {
+	_, err := sl.writer.Write(sl.lInfo.formatter(TextFormatter{}).Format(e))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (sl *syslogLogger) Close() {
+	sl.writer.Close()
+}
+
+func (sl *syslogLogger) GetLoggerInfo() *LoggerInfo {
+	return sl.lInfo
+}
+
+// NewSyslogLogger creates a logger that writes into syslog with
+// the given priority and tag, and is using the given LoggerInfo (without the
+// Logger).
+// It returns the logger.
+func NewSyslogLogger(lInfo *LoggerInfo, priority syslog.Priority, tag string) (Logger, error) {
+	writer, err := syslog.New(priority, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogLogger{
+		lInfo:  lInfo,
+		writer: writer,
+	}, nil
+}
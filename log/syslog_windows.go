@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package log
+
+import "errors"
+
+// NewSyslogLogger is unavailable on Windows: the stdlib's log/syslog
+// doesn't implement Dial/New there, so there's no syslog daemon to talk
+// to in the first place. Use NewFileLogger, NewNetLogger or NewJSONLogger
+// instead. The signature takes priority as a plain int (rather than
+// syslog.Priority, which Windows builds of this package can't import) so
+// callers still compile; the value is ignored.
+func NewSyslogLogger(lInfo *LoggerInfo, priority int, tag string) (Logger, error) {
+	return nil, errors.New("log: syslog logging is not supported on windows")
+}